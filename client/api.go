@@ -7,11 +7,15 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/globalsign/mgo/bson"
 	"github.com/golang/glog"
@@ -19,9 +23,9 @@ import (
 )
 
 // getEntity returns the specified entity
-func (c *Client) getEntity(entityRef string) (*Entity, bool, error) {
+func (c *Client) getEntity(ctx context.Context, entityRef string) (*Entity, bool, error) {
 	url := "/v1/entities/" + entityRef
-	entJSON, found, err := c.apiGet(url)
+	entJSON, found, err := c.apiGet(ctx, url)
 	if err != nil {
 		return nil, false, err
 	}
@@ -36,9 +40,9 @@ func (c *Client) getEntity(entityRef string) (*Entity, bool, error) {
 }
 
 // getCollection returns the specified collection
-func (c *Client) getCollection(collectionRef string) (*Collection, bool, error) {
+func (c *Client) getCollection(ctx context.Context, collectionRef string) (*Collection, bool, error) {
 	url := "/v1/collections/" + collectionRef
-	colJSON, found, err := c.apiGet(url)
+	colJSON, found, err := c.apiGet(ctx, url)
 	if err != nil {
 		return nil, false, err
 	}
@@ -53,9 +57,9 @@ func (c *Client) getCollection(collectionRef string) (*Collection, bool, error)
 }
 
 // getContainer returns container by ref id
-func (c *Client) getContainer(containerRef string) (*Container, bool, error) {
+func (c *Client) getContainer(ctx context.Context, containerRef string) (*Container, bool, error) {
 	url := "/v1/containers/" + containerRef
-	conJSON, found, err := c.apiGet(url)
+	conJSON, found, err := c.apiGet(ctx, url)
 	if err != nil {
 		return nil, false, err
 	}
@@ -70,12 +74,12 @@ func (c *Client) getContainer(containerRef string) (*Container, bool, error) {
 }
 
 // createEntity creates an entity (must be authorized)
-func (c *Client) createEntity(name string) (*Entity, error) {
+func (c *Client) createEntity(ctx context.Context, name string) (*Entity, error) {
 	e := Entity{
 		Name:        name,
 		Description: "No description",
 	}
-	entJSON, err := c.apiCreate("/v1/entities", e)
+	entJSON, err := c.apiCreate(ctx, "/v1/entities", e)
 	if err != nil {
 		return nil, err
 	}
@@ -87,13 +91,13 @@ func (c *Client) createEntity(name string) (*Entity, error) {
 }
 
 // createCollection creates a new collection
-func (c *Client) createCollection(name string, entityID string) (*Collection, error) {
+func (c *Client) createCollection(ctx context.Context, name string, entityID string) (*Collection, error) {
 	newCollection := Collection{
 		Name:        name,
 		Description: "No description",
 		Entity:      bson.ObjectIdHex(entityID).Hex(),
 	}
-	colJSON, err := c.apiCreate("/v1/collections", newCollection)
+	colJSON, err := c.apiCreate(ctx, "/v1/collections", newCollection)
 	if err != nil {
 		return nil, err
 	}
@@ -105,13 +109,13 @@ func (c *Client) createCollection(name string, entityID string) (*Collection, er
 }
 
 // createContainer creates a container in the specified collection
-func (c *Client) createContainer(name string, collectionID string) (*Container, error) {
+func (c *Client) createContainer(ctx context.Context, name string, collectionID string) (*Container, error) {
 	newContainer := Container{
 		Name:        name,
 		Description: "No description",
 		Collection:  bson.ObjectIdHex(collectionID).Hex(),
 	}
-	conJSON, err := c.apiCreate("/v1/containers", newContainer)
+	conJSON, err := c.apiCreate(ctx, "/v1/containers", newContainer)
 	if err != nil {
 		return nil, err
 	}
@@ -123,13 +127,13 @@ func (c *Client) createContainer(name string, collectionID string) (*Container,
 }
 
 // createImage creates a new image
-func (c *Client) createImage(hash string, containerID string, description string) (*Image, error) {
+func (c *Client) createImage(ctx context.Context, hash string, containerID string, description string) (*Image, error) {
 	i := Image{
 		Hash:        hash,
 		Description: description,
 		Container:   bson.ObjectIdHex(containerID).Hex(),
 	}
-	imgJSON, err := c.apiCreate("/v1/images", i)
+	imgJSON, err := c.apiCreate(ctx, "/v1/images", i)
 	if err != nil {
 		return nil, err
 	}
@@ -141,9 +145,9 @@ func (c *Client) createImage(hash string, containerID string, description string
 }
 
 // setTags applies tags to the specified container
-func (c *Client) setTags(containerID, imageID string, tags []string) error {
+func (c *Client) setTags(ctx context.Context, containerID, imageID string, tags []string) error {
 	// Get existing tags, so we know which will be replaced
-	existingTags, err := c.getTags(containerID)
+	existingTags, err := c.getTags(ctx, containerID)
 	if err != nil {
 		return err
 	}
@@ -159,7 +163,7 @@ func (c *Client) setTags(containerID, imageID string, tags []string) error {
 			tag,
 			bson.ObjectIdHex(imageID).Hex(),
 		}
-		err := c.setTag(containerID, imgTag)
+		err := c.setTag(ctx, containerID, imgTag)
 		if err != nil {
 			return err
 		}
@@ -167,12 +171,119 @@ func (c *Client) setTags(containerID, imageID string, tags []string) error {
 	return nil
 }
 
+// SearchFilter narrows a search to library entries of a particular kind and
+// attribute value, e.g. {Kind: SearchKindArchitecture, Value: "amd64"}.
+type SearchFilter struct {
+	Kind  SearchFilterKind
+	Value string
+}
+
+// SearchFilterKind identifies what a SearchFilter matches against.
+type SearchFilterKind string
+
+// Recognized SearchFilterKind values, mirroring the resource kinds the
+// library server indexes.
+const (
+	SearchKindEntity       SearchFilterKind = "entity"
+	SearchKindCollection   SearchFilterKind = "collection"
+	SearchKindContainer    SearchFilterKind = "container"
+	SearchKindImage        SearchFilterKind = "image"
+	SearchKindArchitecture SearchFilterKind = "arch"
+	SearchKindSigned       SearchFilterKind = "signed"
+	SearchKindTag          SearchFilterKind = "tag"
+	SearchKindEntityRef    SearchFilterKind = "entityRef"
+)
+
+// validSearchFilterKinds is the set of SearchFilterKind values the server
+// understands; query() rejects anything else rather than silently sending
+// it on to the server.
+var validSearchFilterKinds = map[SearchFilterKind]bool{
+	SearchKindEntity:       true,
+	SearchKindCollection:   true,
+	SearchKindContainer:    true,
+	SearchKindImage:        true,
+	SearchKindArchitecture: true,
+	SearchKindSigned:       true,
+	SearchKindTag:          true,
+	SearchKindEntityRef:    true,
+}
+
+// SearchOptions carries the filters and pagination parameters accepted by
+// Client.SearchWithOptions.
+type SearchOptions struct {
+	Value   string
+	Filters []SearchFilter
+
+	// CreatedBefore/CreatedAfter restrict results to images created within
+	// the given window. Either may be left zero to leave that bound open.
+	CreatedBefore time.Time
+	CreatedAfter  time.Time
+
+	// Limit caps the number of results returned per page; the server
+	// applies its own default/maximum when Limit is zero.
+	Limit int
+	// Cursor resumes a previous search at the page following the one that
+	// produced it, as returned in SearchResults.NextCursor.
+	Cursor string
+}
+
+// query encodes o into URL query parameters understood by /v1/search. It
+// returns an error if o.Filters contains a SearchFilterKind the server does
+// not recognize.
+func (o SearchOptions) query() (url.Values, error) {
+	q := url.Values{}
+	if o.Value != "" {
+		q.Set("value", o.Value)
+	}
+	for _, f := range o.Filters {
+		if !validSearchFilterKinds[f.Kind] {
+			return nil, fmt.Errorf("invalid search filter kind: %q", f.Kind)
+		}
+		q.Add("filter."+string(f.Kind), f.Value)
+	}
+	if !o.CreatedBefore.IsZero() {
+		q.Set("createdBefore", o.CreatedBefore.Format(time.RFC3339))
+	}
+	if !o.CreatedAfter.IsZero() {
+		q.Set("createdAfter", o.CreatedAfter.Format(time.RFC3339))
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	return q, nil
+}
+
 // Search searches library by name, returns any matching collections,
 // containers, entities, or images.
 func (c *Client) Search(value string) (*SearchResults, error) {
-	url := fmt.Sprintf("/v1/search?value=%s", url.QueryEscape(value))
+	return c.SearchCtx(context.Background(), value)
+}
+
+// SearchCtx is the context-aware variant of Search.
+func (c *Client) SearchCtx(ctx context.Context, value string) (*SearchResults, error) {
+	return c.SearchWithOptionsCtx(ctx, SearchOptions{Value: value})
+}
 
-	resJSON, _, err := c.apiGet(url)
+// SearchWithOptions searches the library using the supplied filters and
+// pagination options, returning any matching collections, containers,
+// entities, or images. Callers can page through large result sets by
+// passing SearchResults.NextCursor back in as SearchOptions.Cursor.
+func (c *Client) SearchWithOptions(o SearchOptions) (*SearchResults, error) {
+	return c.SearchWithOptionsCtx(context.Background(), o)
+}
+
+// SearchWithOptionsCtx is the context-aware variant of SearchWithOptions.
+func (c *Client) SearchWithOptionsCtx(ctx context.Context, o SearchOptions) (*SearchResults, error) {
+	q, err := o.query()
+	if err != nil {
+		return nil, err
+	}
+	url := "/v1/search?" + q.Encode()
+
+	resJSON, _, err := c.apiGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -185,13 +296,27 @@ func (c *Client) Search(value string) (*SearchResults, error) {
 	return &res.Data, nil
 }
 
-func (c *Client) apiCreate(url string, o interface{}) (objJSON []byte, err error) {
+func (c *Client) apiCreate(ctx context.Context, url string, o interface{}) (objJSON []byte, err error) {
 	glog.V(2).Infof("apiCreate calling %s", url)
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+
+	if c.progress != nil {
+		c.progress.OnStart("create", url)
+		defer func() { c.progress.OnComplete(err) }()
+	}
+
 	s, err := json.Marshal(o)
 	if err != nil {
 		return []byte{}, fmt.Errorf("error encoding object to JSON:\n\t%v", err)
 	}
-	req, err := c.newRequest("POST", url, "", bytes.NewBuffer(s))
+
+	var body io.Reader = bytes.NewBuffer(s)
+	if c.progress != nil {
+		body = &progressReader{r: body, total: int64(len(s)), reporter: c.progress}
+	}
+
+	req, err := c.newRequest(ctx, "POST", url, "", body)
 	if err != nil {
 		return []byte{}, fmt.Errorf("error creating POST request:\n\t%v", err)
 	}
@@ -211,11 +336,21 @@ func (c *Client) apiCreate(url string, o interface{}) (objJSON []byte, err error
 	if err != nil {
 		return []byte{}, fmt.Errorf("error reading response from server:\n\t%v", err)
 	}
+
+	if c.progress != nil {
+		var status statusEnvelope
+		if jsonErr := json.Unmarshal(objJSON, &status); jsonErr == nil && status.Status != "" {
+			c.progress.OnStatus(status.Status)
+		}
+	}
+
 	return objJSON, nil
 }
 
-func (c *Client) apiGet(path string) (objJSON []byte, found bool, err error) {
+func (c *Client) apiGet(ctx context.Context, path string) (objJSON []byte, found bool, err error) {
 	glog.V(2).Infof("apiGet calling %s", path)
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
 
 	// split url containing query into component pieces (path and raw query)
 	u, err := url.Parse(path)
@@ -223,7 +358,7 @@ func (c *Client) apiGet(path string) (objJSON []byte, found bool, err error) {
 		return []byte{}, false, fmt.Errorf("error parsing url:\n\t%v", err)
 	}
 
-	req, err := c.newRequest(http.MethodGet, u.Path, u.RawQuery, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, u.Path, u.RawQuery, nil)
 	if err != nil {
 		return []byte{}, false, fmt.Errorf("error creating request to server:\n\t%v", err)
 	}
@@ -250,11 +385,53 @@ func (c *Client) apiGet(path string) (objJSON []byte, found bool, err error) {
 	return []byte{}, false, fmt.Errorf("error reading response from server")
 }
 
+// apiDelete issues a DELETE to path, treating 200 and 204 as success and 404
+// as a not-found error. For 404 and any other status it decodes the jsonresp
+// error body, if any, into the returned error.
+func (c *Client) apiDelete(ctx context.Context, path string) error {
+	glog.V(2).Infof("apiDelete calling %s", path)
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+
+	// split url containing query into component pieces (path and raw query)
+	u, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("error parsing url:\n\t%v", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodDelete, u.Path, u.RawQuery, nil)
+	if err != nil {
+		return fmt.Errorf("error creating DELETE request:\n\t%v", err)
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request to server:\n\t%v", err)
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		if err := jsonresp.ReadError(res.Body); err != nil {
+			return fmt.Errorf("delete did not succeed: %v", err)
+		}
+		return fmt.Errorf("delete did not succeed: not found")
+	default:
+		if err := jsonresp.ReadError(res.Body); err != nil {
+			return fmt.Errorf("delete did not succeed: %v", err)
+		}
+		return fmt.Errorf("delete did not succeed: http status code: %d", res.StatusCode)
+	}
+}
+
 // getTags returns a tag map for the specified containerID
-func (c *Client) getTags(containerID string) (TagMap, error) {
+func (c *Client) getTags(ctx context.Context, containerID string) (TagMap, error) {
 	url := fmt.Sprintf("/v1/tags/%s", containerID)
 	glog.V(2).Infof("getTags calling %s", url)
-	req, err := c.newRequest(http.MethodGet, url, "", nil)
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, http.MethodGet, url, "", nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request to server:\n\t%v", err)
 	}
@@ -278,14 +455,17 @@ func (c *Client) getTags(containerID string) (TagMap, error) {
 }
 
 // setTag sets tag on specified containerID
-func (c *Client) setTag(containerID string, t ImageTag) error {
+func (c *Client) setTag(ctx context.Context, containerID string, t ImageTag) error {
 	url := "/v1/tags/" + containerID
 	glog.V(2).Infof("setTag calling %s", url)
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+
 	s, err := json.Marshal(t)
 	if err != nil {
 		return fmt.Errorf("error encoding object to JSON:\n\t%v", err)
 	}
-	req, err := c.newRequest("POST", url, "", bytes.NewBuffer(s))
+	req, err := c.newRequest(ctx, "POST", url, "", bytes.NewBuffer(s))
 	if err != nil {
 		return fmt.Errorf("error creating POST request:\n\t%v", err)
 	}
@@ -305,8 +485,13 @@ func (c *Client) setTag(containerID string, t ImageTag) error {
 
 // GetImage returns the Image object if exists, otherwise returns error
 func (c *Client) GetImage(imageRef string) (*Image, bool, error) {
+	return c.GetImageCtx(context.Background(), imageRef)
+}
+
+// GetImageCtx is the context-aware variant of GetImage.
+func (c *Client) GetImageCtx(ctx context.Context, imageRef string) (*Image, bool, error) {
 	url := "/v1/images/" + imageRef
-	imgJSON, found, err := c.apiGet(url)
+	imgJSON, found, err := c.apiGet(ctx, url)
 	if err != nil {
 		return nil, false, err
 	}
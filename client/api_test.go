@@ -0,0 +1,81 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestDeleteImageForceQuery ensures that the force=true query parameter
+// passed to DeleteImage actually reaches the server, rather than being
+// dropped by apiDelete building the request with a blank rawQuery.
+func TestDeleteImageForceQuery(t *testing.T) {
+	var sawQuery string
+
+	mux := http.NewServeMux()
+	// DeleteImage must resolve the ref via a direct image lookup only, the
+	// same way GetImage does, not the full entity/collection/container
+	// chain that Resolve walks.
+	mux.HandleFunc("/v1/entities/", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected entity lookup: %s", r.URL.Path)
+	})
+	mux.HandleFunc("/v1/collections/", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected collection lookup: %s", r.URL.Path)
+	})
+	mux.HandleFunc("/v1/containers/", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected container lookup: %s", r.URL.Path)
+	})
+	mux.HandleFunc("/v1/images/e/c/con:latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"img1","hash":"sha256:abc","container":"con1"}}`))
+	})
+	mux.HandleFunc("/v1/images/img1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		sawQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+	if err := c.DeleteImage("e/c/con:latest", true); err != nil {
+		t.Fatalf("DeleteImage returned error: %v", err)
+	}
+	if sawQuery != "force=true" {
+		t.Fatalf("expected query %q, got %q", "force=true", sawQuery)
+	}
+}
+
+// TestApiDeleteNotFoundSurfacesServerMessage ensures a 404 response body is
+// still decoded via jsonresp.ReadError, rather than being discarded in
+// favor of a generic "not found" message.
+func TestApiDeleteNotFoundSurfacesServerMessage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/images/img1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":404,"message":"already deleted by another build"}}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+	err := c.apiDelete(context.Background(), "/v1/images/img1")
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+	if !strings.Contains(err.Error(), "already deleted by another build") {
+		t.Fatalf("expected server message in error, got: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Client describes the context for communicating with a Sylabs Cloud
+// library server.
+type Client struct {
+	BaseURL    string
+	AuthToken  string
+	HTTPClient *http.Client
+
+	// defaultTimeout bounds how long a request may run when the caller's
+	// context carries no deadline of its own. Zero means no default.
+	defaultTimeout time.Duration
+	// requestHook, when set, is called on every outgoing request before it
+	// is sent, letting callers inject headers such as X-Request-ID.
+	requestHook func(*http.Request)
+	// progress, when set, receives structured events for long-running
+	// operations such as image creation.
+	progress ProgressReporter
+}
+
+// ClientOption configures optional Client behavior.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the http.Client used to perform requests.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithDefaultTimeout sets a per-request timeout applied to calls whose
+// context does not already carry a deadline. It is a convenient way to
+// bound requests made through callers (e.g. a CLI) that do not thread a
+// timeout of their own through context.Context.
+func WithDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultTimeout = d
+	}
+}
+
+// WithProgress installs a ProgressReporter that receives OnStart/OnProgress/
+// OnStatus/OnComplete events for long-running operations such as image
+// creation, in place of the default silent glog.V(2) traces.
+func WithProgress(reporter ProgressReporter) ClientOption {
+	return func(c *Client) {
+		c.progress = reporter
+	}
+}
+
+// WithRequestHook installs a hook invoked on every outgoing *http.Request
+// before it is sent, for injecting per-call headers such as X-Request-ID or
+// trace propagation headers.
+func WithRequestHook(hook func(*http.Request)) ClientOption {
+	return func(c *Client) {
+		c.requestHook = hook
+	}
+}
+
+// NewClient returns a Client configured to talk to the library server at
+// baseURL, authenticating with authToken, with any supplied options applied.
+func NewClient(baseURL, authToken string, opts ...ClientOption) *Client {
+	c := &Client{
+		BaseURL:    baseURL,
+		AuthToken:  authToken,
+		HTTPClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// withDefaultTimeout returns ctx bounded by c.defaultTimeout when ctx does
+// not already carry a deadline. The returned CancelFunc must always be
+// called by the caller, typically via defer, to release resources.
+func (c *Client) withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.defaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.defaultTimeout)
+}
+
+// newRequest builds an *http.Request bound to ctx, with authentication and
+// any configured request hook applied.
+func (c *Client) newRequest(ctx context.Context, method, path, rawQuery string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = rawQuery
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+	if c.requestHook != nil {
+		c.requestHook(req)
+	}
+	return req, nil
+}
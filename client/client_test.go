@@ -0,0 +1,117 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithDefaultTimeoutAbortsSlowRequest ensures a request whose context
+// carries no deadline of its own is still bounded by WithDefaultTimeout,
+// rather than blocking until the server (or transport) eventually gives up.
+func TestWithDefaultTimeoutAbortsSlowRequest(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()), WithDefaultTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, _, err := c.GetImage("e/c/con:latest")
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected error from a request past its default timeout, got none")
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("request took %s, default timeout did not bound it", elapsed)
+	}
+}
+
+// TestWithDefaultTimeoutDoesNotOverrideCallerDeadline ensures a context that
+// already carries a deadline is left alone by withDefaultTimeout.
+func TestWithDefaultTimeoutDoesNotOverrideCallerDeadline(t *testing.T) {
+	c := NewClient("http://example.invalid", "", WithDefaultTimeout(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	gotCtx, gotCancel := c.withDefaultTimeout(ctx)
+	defer gotCancel()
+
+	if dl, ok := gotCtx.Deadline(); !ok {
+		t.Fatal("expected returned context to carry a deadline")
+	} else if time.Until(dl) > time.Hour {
+		t.Fatalf("expected the caller's short deadline to be preserved, got %s out", time.Until(dl))
+	}
+}
+
+// TestWithRequestHookInjectsHeader ensures a configured request hook runs on
+// every outgoing request and that the server actually receives the header
+// it injects.
+func TestWithRequestHookInjectsHeader(t *testing.T) {
+	var gotHeader string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Request-ID")
+		w.Write([]byte(`{"data":{"id":"img1","hash":"sha256:abc"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()), WithRequestHook(func(req *http.Request) {
+		req.Header.Set("X-Request-ID", "trace-123")
+	}))
+
+	if _, _, err := c.GetImage("e/c/con:latest"); err != nil {
+		t.Fatalf("GetImage returned error: %v", err)
+	}
+	if gotHeader != "trace-123" {
+		t.Fatalf("expected X-Request-ID %q, got %q", "trace-123", gotHeader)
+	}
+}
+
+// TestGetImageCtxHonorsCancellation ensures an explicitly canceled context
+// aborts the request rather than running to completion.
+func TestGetImageCtxHonorsCancellation(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	_, _, err := c.GetImageCtx(ctx, "e/c/con:latest")
+	if err == nil {
+		t.Fatal("expected error from canceled context, got none")
+	}
+	if !strings.Contains(err.Error(), "context canceled") {
+		t.Fatalf("expected context cancellation error, got: %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DeleteEntity deletes the specified entity (must be authorized).
+func (c *Client) DeleteEntity(entityRef string) error {
+	return c.DeleteEntityCtx(context.Background(), entityRef)
+}
+
+// DeleteEntityCtx is the context-aware variant of DeleteEntity.
+func (c *Client) DeleteEntityCtx(ctx context.Context, entityRef string) error {
+	return c.apiDelete(ctx, "/v1/entities/"+entityRef)
+}
+
+// DeleteCollection deletes the specified collection (must be authorized).
+func (c *Client) DeleteCollection(collectionRef string) error {
+	return c.DeleteCollectionCtx(context.Background(), collectionRef)
+}
+
+// DeleteCollectionCtx is the context-aware variant of DeleteCollection.
+func (c *Client) DeleteCollectionCtx(ctx context.Context, collectionRef string) error {
+	return c.apiDelete(ctx, "/v1/collections/"+collectionRef)
+}
+
+// DeleteContainer deletes the specified container (must be authorized).
+func (c *Client) DeleteContainer(containerRef string) error {
+	return c.DeleteContainerCtx(context.Background(), containerRef)
+}
+
+// DeleteContainerCtx is the context-aware variant of DeleteContainer.
+func (c *Client) DeleteContainerCtx(ctx context.Context, containerRef string) error {
+	return c.apiDelete(ctx, "/v1/containers/"+containerRef)
+}
+
+// DeleteImage deletes the image identified by ref, which may be any form
+// accepted by ParseLibraryRef. Deletion of an image that is still tagged
+// fails unless force is true.
+func (c *Client) DeleteImage(ref string, force bool) error {
+	return c.DeleteImageCtx(context.Background(), ref, force)
+}
+
+// DeleteImageCtx is the context-aware variant of DeleteImage.
+func (c *Client) DeleteImageCtx(ctx context.Context, ref string, force bool) error {
+	lr, err := ParseLibraryRef(ref)
+	if err != nil {
+		return err
+	}
+	imageRef := lr.Hash
+	if imageRef == "" {
+		imageRef = lr.String()
+	}
+
+	img, found, err := c.GetImageCtx(ctx, imageRef)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("image %q not found", imageRef)
+	}
+
+	path := "/v1/images/" + img.ID
+	if force {
+		path += "?force=true"
+	}
+	return c.apiDelete(ctx, path)
+}
+
+// UntagImage removes tag from the specified container, leaving the image it
+// pointed at otherwise untouched.
+func (c *Client) UntagImage(containerID, tag string) error {
+	return c.UntagImageCtx(context.Background(), containerID, tag)
+}
+
+// UntagImageCtx is the context-aware variant of UntagImage.
+func (c *Client) UntagImageCtx(ctx context.Context, containerID, tag string) error {
+	return c.apiDelete(ctx, fmt.Sprintf("/v1/tags/%s/%s", containerID, tag))
+}
+
+// listImages returns every image belonging to containerID.
+func (c *Client) listImages(ctx context.Context, containerID string) ([]Image, error) {
+	path := fmt.Sprintf("/v1/containers/%s/images", containerID)
+	imgJSON, found, err := c.apiGet(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	var res ImagesResponse
+	if err := json.Unmarshal(imgJSON, &res); err != nil {
+		return nil, fmt.Errorf("error decoding images: %v", err)
+	}
+	return res.Data, nil
+}
+
+// PruneImages deletes all but the keep most recently created untagged
+// images belonging to containerID, leaving tagged images untouched. This
+// lets CI systems reclaim space from per-branch builds without deleting
+// anything a tag still points at.
+func (c *Client) PruneImages(containerID string, keep int) error {
+	return c.PruneImagesCtx(context.Background(), containerID, keep)
+}
+
+// PruneImagesCtx is the context-aware variant of PruneImages.
+func (c *Client) PruneImagesCtx(ctx context.Context, containerID string, keep int) error {
+	imgs, err := c.listImages(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	tags, err := c.getTags(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	tagged := make(map[string]bool, len(tags))
+	for _, imageID := range tags {
+		tagged[imageID] = true
+	}
+
+	var untagged []Image
+	for _, img := range imgs {
+		if !tagged[img.ID] {
+			untagged = append(untagged, img)
+		}
+	}
+	sort.Slice(untagged, func(i, j int) bool {
+		return untagged[i].CreatedAt.After(untagged[j].CreatedAt)
+	})
+
+	if keep < 0 {
+		keep = 0
+	}
+	if keep >= len(untagged) {
+		return nil
+	}
+	for _, img := range untagged[keep:] {
+		if err := c.apiDelete(ctx, "/v1/images/"+img.ID); err != nil {
+			return fmt.Errorf("error pruning image %s: %v", img.Hash, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newPruneStubServer serves a container with two tagged images (t1, t2) and
+// three untagged images (u1 oldest, u2, u3 newest), recording every image ID
+// that gets a DELETE request.
+func newPruneStubServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var deleted []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/containers/con1/images", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[
+			{"id":"t1","hash":"ht1","container":"con1","createdAt":"2024-01-01T00:00:00Z"},
+			{"id":"t2","hash":"ht2","container":"con1","createdAt":"2024-01-02T00:00:00Z"},
+			{"id":"u1","hash":"hu1","container":"con1","createdAt":"2024-01-03T00:00:00Z"},
+			{"id":"u2","hash":"hu2","container":"con1","createdAt":"2024-01-04T00:00:00Z"},
+			{"id":"u3","hash":"hu3","container":"con1","createdAt":"2024-01-05T00:00:00Z"}
+		]}`)
+	})
+	mux.HandleFunc("/v1/tags/con1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"v1":"t1","v2":"t2"}}`)
+	})
+	mux.HandleFunc("/v1/images/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Fatalf("unexpected method %s %s", r.Method, r.URL.Path)
+		}
+		deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/v1/images/"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return httptest.NewServer(mux), &deleted
+}
+
+func TestPruneImages(t *testing.T) {
+	tests := []struct {
+		name        string
+		keep        int
+		wantDeleted []string
+	}{
+		{name: "keep zero deletes all untagged", keep: 0, wantDeleted: []string{"u1", "u2", "u3"}},
+		{name: "keep one deletes all but newest", keep: 1, wantDeleted: []string{"u1", "u2"}},
+		{name: "keep exact count deletes nothing", keep: 3, wantDeleted: nil},
+		{name: "keep more than count deletes nothing", keep: 5, wantDeleted: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, deleted := newPruneStubServer(t)
+			defer srv.Close()
+
+			c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+			if err := c.PruneImages("con1", tt.keep); err != nil {
+				t.Fatalf("PruneImages returned error: %v", err)
+			}
+
+			got := append([]string{}, (*deleted)...)
+			sort.Strings(got)
+			want := append([]string{}, tt.wantDeleted...)
+			sort.Strings(want)
+
+			if len(got) != len(want) {
+				t.Fatalf("got deleted %v, want %v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("got deleted %v, want %v", got, want)
+				}
+			}
+			for _, id := range got {
+				if id == "t1" || id == "t2" {
+					t.Fatalf("tagged image %s was deleted", id)
+				}
+			}
+		})
+	}
+}
+
+// TestListImagesEndpointShape confirms listImages calls the
+// /v1/containers/{id}/images endpoint and decodes its response correctly.
+func TestListImagesEndpointShape(t *testing.T) {
+	var gotPath string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/containers/con1/images", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		fmt.Fprint(w, `{"data":[{"id":"img1","hash":"h1","container":"con1"}]}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+	imgs, err := c.listImages(context.Background(), "con1")
+	if err != nil {
+		t.Fatalf("listImages returned error: %v", err)
+	}
+	if gotPath != "/v1/containers/con1/images" {
+		t.Fatalf("got path %q, want %q", gotPath, "/v1/containers/con1/images")
+	}
+	if len(imgs) != 1 || imgs[0].ID != "img1" {
+		t.Fatalf("unexpected images: %+v", imgs)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ImageHistoryEntry describes one revision in the ancestry of an image, as
+// returned by Client.GetImageHistory.
+type ImageHistoryEntry struct {
+	Hash        string
+	Created     time.Time
+	CreatedBy   string
+	Size        int64
+	Tags        []string
+	Description string
+}
+
+// invertTags turns a TagMap (tag -> imageID) into the reverse lookup,
+// imageID -> tags, so the caller can annotate an image with every tag that
+// currently points at it.
+func invertTags(tags TagMap) map[string][]string {
+	inv := make(map[string][]string, len(tags))
+	for tag, imageID := range tags {
+		inv[imageID] = append(inv[imageID], tag)
+	}
+	return inv
+}
+
+// GetImageHistory returns the newest-first list of prior revisions of the
+// image identified by ref, starting with the image itself and walking back
+// through its ParentImage chain. Each entry is annotated with the tags
+// currently pointing at it.
+func (c *Client) GetImageHistory(ref string) ([]ImageHistoryEntry, error) {
+	return c.GetImageHistoryCtx(context.Background(), ref)
+}
+
+// GetImageHistoryCtx is the context-aware variant of GetImageHistory.
+func (c *Client) GetImageHistoryCtx(ctx context.Context, ref string) ([]ImageHistoryEntry, error) {
+	img, _, _, con, err := c.ResolveCtx(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := c.getTags(ctx, con.ID)
+	if err != nil {
+		return nil, err
+	}
+	tagsByImage := invertTags(tags)
+
+	var history []ImageHistoryEntry
+	seen := make(map[string]bool)
+	for img != nil {
+		if seen[img.ID] {
+			return nil, fmt.Errorf("cycle detected in image history at %s", img.Hash)
+		}
+		seen[img.ID] = true
+
+		history = append(history, ImageHistoryEntry{
+			Hash:        img.Hash,
+			Created:     img.CreatedAt,
+			CreatedBy:   img.CreatedBy,
+			Size:        img.Size,
+			Tags:        tagsByImage[img.ID],
+			Description: img.Description,
+		})
+
+		if img.ParentImage == "" {
+			break
+		}
+		var found bool
+		img, found, err = c.GetImageCtx(ctx, img.ParentImage)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+	}
+	return history, nil
+}
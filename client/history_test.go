@@ -0,0 +1,112 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newResolveStubMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/entities/e", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"e1","name":"e"}}`)
+	})
+	mux.HandleFunc("/v1/collections/e/c", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"c1","name":"c","entity":"e1"}}`)
+	})
+	mux.HandleFunc("/v1/containers/e/c/con", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"con1","name":"con","collection":"c1"}}`)
+	})
+	return mux
+}
+
+// TestGetImageHistoryWalksParentChain serves a 3-deep ParentImage chain
+// alongside a tag map, and checks that GetImageHistory both walks the whole
+// chain in order and annotates each entry with the tags pointing at it.
+func TestGetImageHistoryWalksParentChain(t *testing.T) {
+	mux := newResolveStubMux()
+	mux.HandleFunc("/v1/images/e/c/con:latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"img3","hash":"h3","container":"con1","parentImage":"img2"}}`)
+	})
+	mux.HandleFunc("/v1/images/img2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"img2","hash":"h2","container":"con1","parentImage":"img1"}}`)
+	})
+	mux.HandleFunc("/v1/images/img1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"img1","hash":"h1","container":"con1"}}`)
+	})
+	mux.HandleFunc("/v1/tags/con1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"latest":"img3","v1":"img1"}}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+	history, err := c.GetImageHistory("e/c/con:latest")
+	if err != nil {
+		t.Fatalf("GetImageHistory returned error: %v", err)
+	}
+
+	wantHashes := []string{"h3", "h2", "h1"}
+	if len(history) != len(wantHashes) {
+		t.Fatalf("got %d entries, want %d: %+v", len(history), len(wantHashes), history)
+	}
+	for i, want := range wantHashes {
+		if history[i].Hash != want {
+			t.Fatalf("entry %d: got hash %q, want %q", i, history[i].Hash, want)
+		}
+	}
+	if len(history[0].Tags) != 1 || history[0].Tags[0] != "latest" {
+		t.Fatalf("entry 0: got tags %v, want [latest]", history[0].Tags)
+	}
+	if len(history[2].Tags) != 1 || history[2].Tags[0] != "v1" {
+		t.Fatalf("entry 2: got tags %v, want [v1]", history[2].Tags)
+	}
+}
+
+// TestGetImageHistoryDetectsCycle serves a ParentImage cycle and checks that
+// GetImageHistory returns the cycle-detected error instead of looping
+// forever.
+func TestGetImageHistoryDetectsCycle(t *testing.T) {
+	mux := newResolveStubMux()
+	mux.HandleFunc("/v1/images/e/c/con:latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"imgA","hash":"hA","container":"con1","parentImage":"imgB"}}`)
+	})
+	mux.HandleFunc("/v1/images/imgA", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"imgA","hash":"hA","container":"con1","parentImage":"imgB"}}`)
+	})
+	mux.HandleFunc("/v1/images/imgB", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"imgB","hash":"hB","container":"con1","parentImage":"imgA"}}`)
+	})
+	mux.HandleFunc("/v1/tags/con1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{}}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.GetImageHistory("e/c/con:latest")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected cycle-detected error, got none")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetImageHistory did not return, cycle detection appears to loop forever")
+	}
+}
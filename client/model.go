@@ -0,0 +1,103 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import "time"
+
+// Entity is the owner of collections of containers
+type Entity struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Collection groups together containers belonging to an Entity
+type Collection struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Entity      string `json:"entity"`
+}
+
+// Container holds the images published under a Collection
+type Container struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Collection  string `json:"collection"`
+}
+
+// Image is a single SIF image belonging to a Container
+type Image struct {
+	ID          string    `json:"id,omitempty"`
+	Hash        string    `json:"hash"`
+	Description string    `json:"description"`
+	Container   string    `json:"container"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+	// ParentImage is the ID of the image this one was pushed to replace on
+	// the same container, if any, forming a revision chain read by
+	// Client.GetImageHistory.
+	ParentImage string `json:"parentImage,omitempty"`
+}
+
+// ImageTag associates a tag with an image ID
+type ImageTag struct {
+	Tag     string `json:"tag"`
+	ImageID string `json:"imageID"`
+}
+
+// TagMap maps a tag name to the ID of the image it refers to
+type TagMap map[string]string
+
+// EntityResponse wraps an Entity returned by the server
+type EntityResponse struct {
+	Data Entity `json:"data"`
+}
+
+// CollectionResponse wraps a Collection returned by the server
+type CollectionResponse struct {
+	Data Collection `json:"data"`
+}
+
+// ContainerResponse wraps a Container returned by the server
+type ContainerResponse struct {
+	Data Container `json:"data"`
+}
+
+// ImageResponse wraps an Image returned by the server
+type ImageResponse struct {
+	Data Image `json:"data"`
+}
+
+// TagsResponse wraps a TagMap returned by the server
+type TagsResponse struct {
+	Data TagMap `json:"data"`
+}
+
+// ImagesResponse wraps a list of Images returned by the server
+type ImagesResponse struct {
+	Data []Image `json:"data"`
+}
+
+// SearchResults holds the matches returned by a library search
+type SearchResults struct {
+	Entities    []Entity     `json:"entities"`
+	Collections []Collection `json:"collections"`
+	Containers  []Container  `json:"containers"`
+	Images      []Image      `json:"images"`
+	// Total is the number of results matching the search, across all pages.
+	Total int `json:"total"`
+	// NextCursor is the cursor token to pass as SearchOptions.Cursor to fetch
+	// the next page of results. It is empty when there are no more results.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// SearchResponse wraps SearchResults returned by the server
+type SearchResponse struct {
+	Data SearchResults `json:"data"`
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ProgressReporter receives structured events for a single long-running
+// operation (the creation requests sent through apiCreate), letting a caller drive a
+// progress bar and status label independently of each other.
+type ProgressReporter interface {
+	// OnStart is called once, before the operation's request is sent.
+	OnStart(op, ref string)
+	// OnProgress is called as the request body is transferred. bytesTotal
+	// is 0 when the size is not known in advance.
+	OnProgress(bytesDone, bytesTotal int64)
+	// OnStatus is called whenever the server reports a status transition
+	// for the operation, e.g. "uploading", "verifying", "signed".
+	OnStatus(msg string)
+	// OnComplete is called once the operation has finished, with a nil err
+	// on success.
+	OnComplete(err error)
+}
+
+// progressEvent is the wire format emitted by JSONProgressWriter, one per
+// line, modeled on Docker's jsonmessage stream.
+type progressEvent struct {
+	Op         string `json:"op,omitempty"`
+	Ref        string `json:"ref,omitempty"`
+	BytesDone  int64  `json:"bytesDone,omitempty"`
+	BytesTotal int64  `json:"bytesTotal,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Complete   bool   `json:"complete,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// JSONProgressWriter is a ProgressReporter that writes each event as a line
+// of JSON, suitable for piping into a CLI TTY or a web UI.
+type JSONProgressWriter struct {
+	w   io.Writer
+	enc *json.Encoder
+
+	op  string
+	ref string
+}
+
+// NewJSONProgressWriter returns a ProgressReporter that writes JSON-line
+// events to w.
+func NewJSONProgressWriter(w io.Writer) *JSONProgressWriter {
+	return &JSONProgressWriter{w: w, enc: json.NewEncoder(w)}
+}
+
+// OnStart implements ProgressReporter.
+func (p *JSONProgressWriter) OnStart(op, ref string) {
+	p.op, p.ref = op, ref
+	p.enc.Encode(progressEvent{Op: op, Ref: ref}) //nolint:errcheck
+}
+
+// OnProgress implements ProgressReporter.
+func (p *JSONProgressWriter) OnProgress(bytesDone, bytesTotal int64) {
+	p.enc.Encode(progressEvent{ //nolint:errcheck
+		Op: p.op, Ref: p.ref,
+		BytesDone: bytesDone, BytesTotal: bytesTotal,
+	})
+}
+
+// OnStatus implements ProgressReporter.
+func (p *JSONProgressWriter) OnStatus(msg string) {
+	p.enc.Encode(progressEvent{Op: p.op, Ref: p.ref, Status: msg}) //nolint:errcheck
+}
+
+// OnComplete implements ProgressReporter.
+func (p *JSONProgressWriter) OnComplete(err error) {
+	ev := progressEvent{Op: p.op, Ref: p.ref, Complete: true}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	p.enc.Encode(ev) //nolint:errcheck
+}
+
+// progressReader wraps an io.Reader, reporting bytes read to a
+// ProgressReporter as the request body is streamed to the server.
+type progressReader struct {
+	r        io.Reader
+	total    int64
+	done     int64
+	reporter ProgressReporter
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.done += int64(n)
+		pr.reporter.OnProgress(pr.done, pr.total)
+	}
+	return n, err
+}
+
+// statusEnvelope extracts the optional server-reported status transition
+// (e.g. "uploading", "verifying", "signed") carried alongside the usual
+// Data payload in a creation response.
+type statusEnvelope struct {
+	Status string `json:"status"`
+}
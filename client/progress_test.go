@@ -0,0 +1,133 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestJSONProgressWriterWireFormat asserts the exact JSON-line shape emitted
+// for each ProgressReporter event.
+func TestJSONProgressWriterWireFormat(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewJSONProgressWriter(&buf)
+
+	p.OnStart("create", "/v1/images")
+	p.OnProgress(5, 10)
+	p.OnStatus("uploading")
+	p.OnComplete(nil)
+
+	dec := json.NewDecoder(&buf)
+
+	var start progressEvent
+	if err := dec.Decode(&start); err != nil {
+		t.Fatalf("decoding start event: %v", err)
+	}
+	if start.Op != "create" || start.Ref != "/v1/images" {
+		t.Fatalf("unexpected start event: %+v", start)
+	}
+
+	var progress progressEvent
+	if err := dec.Decode(&progress); err != nil {
+		t.Fatalf("decoding progress event: %v", err)
+	}
+	if progress.BytesDone != 5 || progress.BytesTotal != 10 {
+		t.Fatalf("unexpected progress event: %+v", progress)
+	}
+
+	var status progressEvent
+	if err := dec.Decode(&status); err != nil {
+		t.Fatalf("decoding status event: %v", err)
+	}
+	if status.Status != "uploading" {
+		t.Fatalf("unexpected status event: %+v", status)
+	}
+
+	var complete progressEvent
+	if err := dec.Decode(&complete); err != nil {
+		t.Fatalf("decoding complete event: %v", err)
+	}
+	if !complete.Complete || complete.Error != "" {
+		t.Fatalf("unexpected complete event: %+v", complete)
+	}
+}
+
+// recordingReporter is a ProgressReporter that records the order and
+// arguments of every call it receives.
+type recordingReporter struct {
+	calls []string
+
+	completeErr error
+	statuses    []string
+	sawFullRead bool
+}
+
+func (r *recordingReporter) OnStart(op, ref string) {
+	r.calls = append(r.calls, "start:"+op+":"+ref)
+}
+
+func (r *recordingReporter) OnProgress(bytesDone, bytesTotal int64) {
+	r.calls = append(r.calls, "progress")
+	if bytesTotal > 0 && bytesDone == bytesTotal {
+		r.sawFullRead = true
+	}
+}
+
+func (r *recordingReporter) OnStatus(msg string) {
+	r.calls = append(r.calls, "status")
+	r.statuses = append(r.statuses, msg)
+}
+
+func (r *recordingReporter) OnComplete(err error) {
+	r.calls = append(r.calls, "complete")
+	r.completeErr = err
+}
+
+// TestApiCreateEmitsProgressEvents ensures apiCreate drives a configured
+// ProgressReporter through OnStart -> OnProgress -> OnStatus -> OnComplete,
+// with the server's reported status transition surfaced and the real error
+// (nil, on success) delivered to OnComplete.
+func TestApiCreateEmitsProgressEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/entities", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":"e1","name":"name","description":"No description"},"status":"verifying"}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	reporter := &recordingReporter{}
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()), WithProgress(reporter))
+
+	if _, err := c.createEntity(context.Background(), "name"); err != nil {
+		t.Fatalf("createEntity returned error: %v", err)
+	}
+
+	wantOrder := []string{"start:create:/v1/entities", "progress", "status", "complete"}
+	if len(reporter.calls) != len(wantOrder) {
+		t.Fatalf("got calls %v, want %v", reporter.calls, wantOrder)
+	}
+	for i, want := range wantOrder {
+		if reporter.calls[i] != want {
+			t.Fatalf("call %d: got %q, want %q (full: %v)", i, reporter.calls[i], want, reporter.calls)
+		}
+	}
+	if reporter.completeErr != nil {
+		t.Fatalf("OnComplete received unexpected error: %v", reporter.completeErr)
+	}
+	if len(reporter.statuses) != 1 || reporter.statuses[0] != "verifying" {
+		t.Fatalf("got statuses %v, want [verifying]", reporter.statuses)
+	}
+	if !reporter.sawFullRead {
+		t.Fatal("OnProgress never reported bytesDone == bytesTotal")
+	}
+}
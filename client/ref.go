@@ -0,0 +1,188 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// LibraryRef is a parsed reference to a library entity, collection,
+// container, tag, or image hash, as accepted by ParseLibraryRef.
+type LibraryRef struct {
+	Entity     string
+	Collection string
+	Container  string
+	Tag        string
+	Hash       string
+}
+
+// String returns the canonical "entity/collection/container:tag" form of
+// ref, or "entity/collection/container@hash" if ref carries a hash instead
+// of a tag.
+func (ref LibraryRef) String() string {
+	path := strings.Join([]string{ref.Entity, ref.Collection, ref.Container}, "/")
+	switch {
+	case ref.Hash != "":
+		return path + "@" + ref.Hash
+	case ref.Tag != "":
+		return path + ":" + ref.Tag
+	default:
+		return path
+	}
+}
+
+// ParseLibraryRef parses a library reference in one of the forms:
+//
+//	library://entity/collection/container:tag
+//	entity/collection/container:tag
+//	entity/collection/container@sha256:...
+//	entity/collection/container
+//	<bson ObjectId hex>
+//
+// A ref missing an explicit tag defaults to "latest". A bare ObjectId hex
+// is returned with only Hash populated, leaving the caller to resolve it
+// via Client.GetImage.
+func ParseLibraryRef(ref string) (LibraryRef, error) {
+	s := strings.TrimPrefix(ref, "library://")
+
+	if bson.IsObjectIdHex(s) {
+		return LibraryRef{Hash: s}, nil
+	}
+
+	path := s
+	var tag, hash string
+	if i := strings.Index(s, "@"); i != -1 {
+		path, hash = s[:i], s[i+1:]
+	} else if i := strings.LastIndex(s, ":"); i != -1 {
+		path, tag = s[:i], s[i+1:]
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 {
+		return LibraryRef{}, fmt.Errorf("invalid library ref %q: expected entity/collection/container", ref)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return LibraryRef{}, fmt.Errorf("invalid library ref %q: empty path component", ref)
+		}
+	}
+
+	if tag == "" && hash == "" {
+		tag = "latest"
+	}
+
+	return LibraryRef{
+		Entity:     parts[0],
+		Collection: parts[1],
+		Container:  parts[2],
+		Tag:        tag,
+		Hash:       hash,
+	}, nil
+}
+
+// Resolve resolves ref to its Image along with the full Entity, Collection,
+// and Container chain it belongs to. It accepts any form understood by
+// ParseLibraryRef.
+func (c *Client) Resolve(ref string) (*Image, *Entity, *Collection, *Container, error) {
+	return c.ResolveCtx(context.Background(), ref)
+}
+
+// ResolveCtx is the context-aware variant of Resolve.
+func (c *Client) ResolveCtx(ctx context.Context, ref string) (*Image, *Entity, *Collection, *Container, error) {
+	lr, err := ParseLibraryRef(ref)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// A bare ObjectId hex carries no entity/collection/container path, so
+	// resolve the image by ID first and walk back up its Container,
+	// Collection and Entity fields instead of querying them with a blank
+	// ref.
+	if lr.Hash != "" && lr.Entity == "" {
+		return c.resolveByHash(ctx, lr.Hash)
+	}
+
+	ent, found, err := c.getEntity(ctx, lr.Entity)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("entity %q not found", lr.Entity)
+	}
+
+	col, found, err := c.getCollection(ctx, lr.Entity+"/"+lr.Collection)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("collection %q not found", lr.Collection)
+	}
+
+	con, found, err := c.getContainer(ctx, lr.Entity+"/"+lr.Collection+"/"+lr.Container)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("container %q not found", lr.Container)
+	}
+
+	imageRef := lr.String()
+	if lr.Hash != "" {
+		imageRef = lr.Hash
+	}
+	img, found, err := c.GetImageCtx(ctx, imageRef)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("image %q not found", imageRef)
+	}
+
+	return img, ent, col, con, nil
+}
+
+// resolveByHash resolves a bare image hash (ObjectId hex) by looking the
+// image up directly, then working backward through its Container,
+// Collection and Entity fields to fill in the rest of the chain.
+func (c *Client) resolveByHash(ctx context.Context, hash string) (*Image, *Entity, *Collection, *Container, error) {
+	img, found, err := c.GetImageCtx(ctx, hash)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("image %q not found", hash)
+	}
+
+	con, found, err := c.getContainer(ctx, img.Container)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("container %q not found", img.Container)
+	}
+
+	col, found, err := c.getCollection(ctx, con.Collection)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("collection %q not found", con.Collection)
+	}
+
+	ent, found, err := c.getEntity(ctx, col.Entity)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, nil, fmt.Errorf("entity %q not found", col.Entity)
+	}
+
+	return img, ent, col, con, nil
+}
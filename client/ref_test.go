@@ -0,0 +1,107 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseLibraryRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		want    LibraryRef
+		wantErr bool
+	}{
+		{
+			name: "library scheme with tag",
+			ref:  "library://e/c/con:tag",
+			want: LibraryRef{Entity: "e", Collection: "c", Container: "con", Tag: "tag"},
+		},
+		{
+			name: "bare path with tag",
+			ref:  "e/c/con:tag",
+			want: LibraryRef{Entity: "e", Collection: "c", Container: "con", Tag: "tag"},
+		},
+		{
+			name: "bare path defaults to latest",
+			ref:  "e/c/con",
+			want: LibraryRef{Entity: "e", Collection: "c", Container: "con", Tag: "latest"},
+		},
+		{
+			name: "path with hash",
+			ref:  "e/c/con@sha256:abc",
+			want: LibraryRef{Entity: "e", Collection: "c", Container: "con", Hash: "sha256:abc"},
+		},
+		{
+			name: "bare ObjectId hex",
+			ref:  "5f7b1c9e1234567890abcdef",
+			want: LibraryRef{Hash: "5f7b1c9e1234567890abcdef"},
+		},
+		{
+			name:    "invalid path",
+			ref:     "e/con",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLibraryRef(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolveByHash ensures a bare ObjectId hex ref is resolved by looking
+// the image up directly and walking back up its Container/Collection/Entity
+// fields, rather than issuing a lookup with a blank entity ref.
+func TestResolveByHash(t *testing.T) {
+	const hash = "5f7b1c9e1234567890abcdef"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/images/"+hash, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"img1","hash":"` + hash + `","container":"con1"}}`))
+	})
+	mux.HandleFunc("/v1/containers/con1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"con1","name":"con","collection":"col1"}}`))
+	})
+	mux.HandleFunc("/v1/collections/col1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"col1","name":"c","entity":"ent1"}}`))
+	})
+	mux.HandleFunc("/v1/entities/ent1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"ent1","name":"e"}}`))
+	})
+	mux.HandleFunc("/v1/entities/", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected lookup with blank entity ref: %s", r.URL.Path)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", WithHTTPClient(srv.Client()))
+
+	img, ent, col, con, err := c.Resolve(hash)
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if img.ID != "img1" || ent.ID != "ent1" || col.ID != "col1" || con.ID != "con1" {
+		t.Fatalf("unexpected resolved chain: img=%+v ent=%+v col=%+v con=%+v", img, ent, col, con)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2018, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import "testing"
+
+func TestSearchOptionsQueryRejectsUnknownFilterKind(t *testing.T) {
+	o := SearchOptions{
+		Value:   "foo",
+		Filters: []SearchFilter{{Kind: SearchFilterKind("bogus"), Value: "x"}},
+	}
+	if _, err := o.query(); err == nil {
+		t.Fatal("expected error for unrecognized filter kind, got none")
+	}
+}
+
+func TestSearchOptionsQueryAcceptsKnownFilterKind(t *testing.T) {
+	o := SearchOptions{
+		Value:   "foo",
+		Filters: []SearchFilter{{Kind: SearchKindArchitecture, Value: "amd64"}},
+	}
+	q, err := o.query()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := q.Get("filter.arch"); got != "amd64" {
+		t.Fatalf("got filter.arch=%q, want %q", got, "amd64")
+	}
+}